@@ -1,8 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,11 +16,14 @@ import (
 
 // Model represents the state of the application
 type model struct {
-	matrixA mat.Matrix // Stores the first user-entered matrix or vector
-	matrixB mat.Matrix // Stores the second user-entered matrix or vector, if needed
-	state   string     // Current state of the application
-	input   string     // Current user input
-	err     error      // Stores any errors that occur
+	matrixA    mat.Matrix // Stores the first user-entered matrix or vector
+	matrixB    mat.Matrix // Stores the second user-entered matrix or vector, if needed
+	state      string     // Current state of the application
+	input      string     // Current user input
+	err        error      // Stores any errors that occur
+	pendingOp  string     // Operation awaiting parameters entered in the inputParams state
+	lastResult mat.Matrix // Last matrix-valued result, available to the "save" command in the result state
+	command    string     // Command typed in the result state (e.g. "save <path>"), kept separate from the displayed result
 }
 
 // initialModel sets up the initial state of the application.
@@ -39,8 +47,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "enter":
 			if m.state == "inputA" {
-				// Parse input into the first matrix
-				m.matrixA, m.err = parseMatrix(m.input)
+				// Parse input into the first matrix, or load it from a file
+				m.matrixA, m.err = parseMatrixInput(m.input)
 				if m.err != nil {
 					m.state = "error" // Switch to the error state if parsing fails
 				} else {
@@ -48,8 +56,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.input = ""       // Clear input after parsing
 				}
 			} else if m.state == "inputB" {
-				// Parse input into the second matrix
-				m.matrixB, m.err = parseMatrix(m.input)
+				// Parse input into the second matrix, or load it from a file
+				m.matrixB, m.err = parseMatrixInput(m.input)
 				if m.err != nil {
 					m.state = "error" // Switch to the error state if parsing fails
 				} else {
@@ -58,83 +66,53 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			} else if m.state == "select" {
 				// Based on user input, proceed to perform an operation or request the second matrix
-				switch m.input {
-				case "det":
-					if isVector(m.matrixA) {
-						m.input = "Determinant is only defined for matrices."
-					} else {
-						det := mat.Det(m.matrixA)
-						m.input = fmt.Sprintf("Determinant: %v", det)
-					}
-					m.state = "result"
-				case "norm":
-					norm := mat.Norm(m.matrixA, 2)
-					m.input = fmt.Sprintf("Norm: %v", norm)
-					m.state = "result"
-				case "nullspace":
-					nullspace, err := calculateNullspace(m.matrixA)
-					if err != nil {
-						m.input = fmt.Sprintf("Error calculating nullspace: %v", err)
-					} else {
-						m.input = fmt.Sprintf("Nullspace:\n%v", matrixToString(nullspace))
-					}
-					m.state = "result"
-				case "inner", "outer", "multiply":
-					// Request the second matrix or vector for further operations
-					m.input = ""
-					m.state = "inputB"
-				default:
-					m.input = "Invalid selection. Please choose 'det', 'norm', 'nullspace', 'inner', 'outer', or 'multiply'.\n"
-					m.state = "select"
-				}
+				m = dispatch(m, selectOps, "select")
 			} else if m.state == "selectOp" {
 				// Perform operations based on the input matrices/vectors
-				switch m.input {
-				case "inner":
-					if isVector(m.matrixA) && isVector(m.matrixB) {
-						inner, _ := innerProduct(m.matrixA.(*mat.Dense), m.matrixB.(*mat.Dense))
-						m.input = fmt.Sprintf("Inner Product: %v", inner)
-					} else {
-						m.input = "Inner product is only defined for vectors."
-					}
-					m.state = "result"
-				case "outer":
-					if isVector(m.matrixA) && isVector(m.matrixB) {
-						outer := outerProduct(m.matrixA.(*mat.Dense), m.matrixB.(*mat.Dense))
-						m.input = fmt.Sprintf("Outer Product:\n%v", matrixToString(outer))
-					} else {
-						m.input = "Outer product is only defined for vectors."
-					}
-					m.state = "result"
-				case "multiply":
-					if !isVector(m.matrixA) && !isVector(m.matrixB) {
-						rA, cA := m.matrixA.Dims()
-						rB, cB := m.matrixB.Dims()
-						if cA != rB {
-							m.input = "Matrices are not compatible for multiplication."
-						} else {
-							product := mat.NewDense(rA, cB, nil)
-							product.Mul(m.matrixA, m.matrixB)
-							m.input = fmt.Sprintf("Matrix Product:\n%v", matrixToString(product))
-						}
-					} else {
-						m.input = "Matrix multiplication is only defined for matrices."
-					}
-					m.state = "result"
-				default:
-					m.input = "Invalid selection for operation. Please choose 'inner', 'outer', or 'multiply'."
-					m.state = "selectOp"
+				m = dispatch(m, twoMatrixOps, "selectOp")
+			} else if m.state == "selectDecomp" {
+				// Compute and display the chosen factorization of matrix A
+				m = dispatch(m, decompositionOps, "selectDecomp")
+			} else if m.state == "inputParams" {
+				// Hand the raw parameter line to the pending operation and run it
+				m.lastResult = nil
+				if handler, ok := paramOps[m.pendingOp]; ok {
+					m = handler(m, strings.TrimSpace(m.input))
+				} else {
+					m.err = fmt.Errorf("unknown pending operation %q", m.pendingOp)
+					m.state = "error"
+				}
+			} else if m.state == "result" && strings.HasPrefix(m.command, "save ") {
+				// Save the last matrix-valued result to the requested path
+				path := strings.TrimSpace(strings.TrimPrefix(m.command, "save "))
+				format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+				if m.lastResult == nil {
+					m.input += "\n\nNo matrix result available to save."
+				} else if err := saveMatrix(path, m.lastResult, format); err != nil {
+					m.input += fmt.Sprintf("\n\nError saving result: %v", err)
+				} else {
+					m.input += fmt.Sprintf("\n\nSaved result to %s.", path)
 				}
+				m.command = ""
 			} else if m.state == "result" || m.state == "error" {
 				m.state = "inputA" // Reset to input state for matrix A
 				m.input = ""       // Clear input
+				m.command = ""     // Clear any unsubmitted command
 			}
 		case "backspace":
-			if len(m.input) > 0 {
+			if m.state == "result" {
+				if len(m.command) > 0 {
+					m.command = m.command[:len(m.command)-1] // Remove the last character from the command
+				}
+			} else if len(m.input) > 0 {
 				m.input = m.input[:len(m.input)-1] // Remove the last character from input
 			}
 		default:
-			m.input += msg.String() // Append typed characters to the input
+			if m.state == "result" {
+				m.command += msg.String() // Append typed characters to the save command
+			} else {
+				m.input += msg.String() // Append typed characters to the input
+			}
 		}
 	}
 
@@ -145,18 +123,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m model) View() string {
 	switch m.state {
 	case "inputA":
-		return "Enter a matrix or vector (comma-separated values, semicolon-separated rows):\n" + m.input + "\n"
+		return "Enter a matrix or vector (comma-separated values, semicolon-separated rows), 'load <path>' to load a CSV/MatrixMarket file, or 'sparse <row,col,value; ...>' (1-indexed) for sparse triplet input:\n" + m.input + "\n"
 	case "inputB":
-		return "Enter a second matrix or vector (comma-separated values, semicolon-separated rows):\n"
+		return "Enter a second matrix or vector (comma-separated values, semicolon-separated rows), or 'load <path>':\n"
 	case "select":
 		matrixView := matrixToString(m.matrixA)
-		return fmt.Sprintf("Matrix A:\n%s\nChoose an operation: det (Determinant), norm (Norm), nullspace (Nullspace), inner (Inner Product), outer (Outer Product), multiply (Matrix Multiplication)\n%s", matrixView, m.input)
+		return fmt.Sprintf("Matrix A:\n%s\nChoose an operation: %s\n%s", matrixView, menu(selectOps), m.input)
 	case "selectOp":
 		matrixViewA := matrixToString(m.matrixA)
 		matrixViewB := matrixToString(m.matrixB)
-		return fmt.Sprintf("Matrix A:\n%s\nMatrix B:\n%s\nChoose an operation: inner (Inner Product), outer (Outer Product), multiply (Matrix Multiplication)\n%s", matrixViewA, matrixViewB, m.input)
+		return fmt.Sprintf("Matrix A:\n%s\nMatrix B:\n%s\nChoose an operation: %s\n%s", matrixViewA, matrixViewB, menu(twoMatrixOps), m.input)
+	case "selectDecomp":
+		matrixView := matrixToString(m.matrixA)
+		return fmt.Sprintf("Matrix A:\n%s\nChoose a decomposition: %s\n%s", matrixView, menu(decompositionOps), m.input)
+	case "inputParams":
+		hint := paramHints[m.pendingOp]
+		if hint == "" {
+			hint = "parameters"
+		}
+		return fmt.Sprintf("Enter %s for %s:\n%s\n", hint, m.pendingOp, m.input)
 	case "result":
-		return m.input + "\n\nPress enter to continue..."
+		return m.input + "\n\nType 'save <path>' to save the last matrix result (.csv or .mtx), or press enter to continue...\n" + m.command
 	case "error":
 		return "Error: " + m.err.Error() + "\n\nPress enter to try again..."
 	default:
@@ -197,33 +184,1256 @@ func parseMatrix(input string) (mat.Matrix, error) {
 	return matrix, nil
 }
 
+// parseMatrixInput parses user input into a matrix. Input of the form
+// "load <path>" is read from disk via loadMatrix, "sparse <triplets>" is
+// parsed as a CSR matrix via parseSparseTriplets, and anything else is
+// parsed as raw comma/semicolon-delimited values via parseMatrix.
+func parseMatrixInput(input string) (mat.Matrix, error) {
+	trimmed := strings.TrimSpace(input)
+	if path, ok := strings.CutPrefix(trimmed, "load "); ok {
+		return loadMatrix(strings.TrimSpace(path))
+	}
+	if triplets, ok := strings.CutPrefix(trimmed, "sparse "); ok {
+		return parseSparseTriplets(triplets)
+	}
+	return parseMatrix(input)
+}
+
+// loadMatrix reads a matrix from path, dispatching on its file extension:
+// ".csv" for a comma/semicolon delimited text matrix (one row per line) and
+// ".mtx" for MatrixMarket coordinate or array format.
+func loadMatrix(path string) (mat.Matrix, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return loadCSV(path)
+	case ".mtx":
+		return loadMatrixMarket(path)
+	default:
+		return nil, fmt.Errorf("unsupported file extension %q", ext)
+	}
+}
+
+// saveMatrix writes m to path in the given format, either "csv" or "mtx".
+func saveMatrix(path string, m mat.Matrix, format string) error {
+	switch strings.ToLower(format) {
+	case "csv":
+		return saveCSV(path, m)
+	case "mtx":
+		return saveMatrixMarket(path, m)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// loadCSV reads a matrix from a comma/semicolon delimited text file, one row
+// per line, matching the delimiters accepted by parseMatrix.
+func loadCSV(path string) (mat.Matrix, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+
+	var rows [][]float64
+	rowLen := -1
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' })
+		row := make([]float64, len(fields))
+		for i, field := range fields {
+			row[i], err = strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q: %v", field, err)
+			}
+		}
+		if rowLen == -1 {
+			rowLen = len(row)
+		} else if len(row) != rowLen {
+			return nil, fmt.Errorf("rows have inconsistent lengths")
+		}
+		rows = append(rows, row)
+	}
+
+	data := make([]float64, 0, len(rows)*rowLen)
+	for _, row := range rows {
+		data = append(data, row...)
+	}
+
+	return mat.NewDense(len(rows), rowLen, data), nil
+}
+
+// saveCSV writes m as a comma-delimited text file, one row per line.
+func saveCSV(path string, m mat.Matrix) error {
+	rows, cols := m.Dims()
+	var sb strings.Builder
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, "%v", m.At(i, j))
+		}
+		sb.WriteString("\n")
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// loadMatrixMarket reads a real-valued, general or symmetric matrix in
+// MatrixMarket coordinate or array format.
+func loadMatrixMarket(path string) (mat.Matrix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("%s: empty file", path)
+	}
+	header := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(header) < 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, fmt.Errorf("%s: missing MatrixMarket header", path)
+	}
+	if header[3] != "real" {
+		return nil, fmt.Errorf("%s: only real-valued MatrixMarket matrices are supported", path)
+	}
+	symmetric := header[4] == "symmetric"
+	if header[4] != "general" && !symmetric {
+		return nil, fmt.Errorf("%s: unsupported MatrixMarket symmetry %q", path, header[4])
+	}
+
+	var sizeLine string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		sizeLine = line
+		break
+	}
+	if sizeLine == "" {
+		return nil, fmt.Errorf("%s: missing size line", path)
+	}
+
+	switch format := header[2]; format {
+	case "coordinate":
+		return loadMatrixMarketCoordinate(scanner, sizeLine, symmetric)
+	case "array":
+		return loadMatrixMarketArray(scanner, sizeLine, symmetric)
+	default:
+		return nil, fmt.Errorf("%s: unsupported MatrixMarket format %q", path, format)
+	}
+}
+
+// loadMatrixMarketCoordinate reads the sparse triplet body of a MatrixMarket
+// coordinate file into a dense matrix.
+func loadMatrixMarketCoordinate(scanner *bufio.Scanner, sizeLine string, symmetric bool) (mat.Matrix, error) {
+	var rows, cols, nnz int
+	if _, err := fmt.Sscanf(sizeLine, "%d %d %d", &rows, &cols, &nnz); err != nil {
+		return nil, fmt.Errorf("invalid coordinate size line %q: %v", sizeLine, err)
+	}
+
+	m := mat.NewDense(rows, cols, nil)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		var i, j int
+		var v float64
+		if _, err := fmt.Sscanf(line, "%d %d %f", &i, &j, &v); err != nil {
+			return nil, fmt.Errorf("invalid coordinate entry %q: %v", line, err)
+		}
+		if i < 1 || i > rows || j < 1 || j > cols {
+			return nil, fmt.Errorf("coordinate entry %q is out of bounds for a %dx%d matrix", line, rows, cols)
+		}
+		m.Set(i-1, j-1, v)
+		if symmetric && i != j {
+			m.Set(j-1, i-1, v)
+		}
+	}
+	return m, nil
+}
+
+// loadMatrixMarketArray reads the dense, column-major body of a MatrixMarket
+// array file into a dense matrix.
+func loadMatrixMarketArray(scanner *bufio.Scanner, sizeLine string, symmetric bool) (mat.Matrix, error) {
+	var rows, cols int
+	if _, err := fmt.Sscanf(sizeLine, "%d %d", &rows, &cols); err != nil {
+		return nil, fmt.Errorf("invalid array size line %q: %v", sizeLine, err)
+	}
+
+	m := mat.NewDense(rows, cols, nil)
+	row, col := 0, 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array entry %q: %v", line, err)
+		}
+		if col >= cols {
+			return nil, fmt.Errorf("array body has more than %d entries for a %dx%d matrix", rows*cols, rows, cols)
+		}
+		m.Set(row, col, v)
+		if symmetric && row != col {
+			m.Set(col, row, v)
+		}
+		row++
+		if row == rows {
+			col++
+			// A symmetric array body stores only the lower triangle, so
+			// column col starts at row col instead of row 0.
+			if symmetric {
+				row = col
+			} else {
+				row = 0
+			}
+		}
+	}
+	return m, nil
+}
+
+// saveMatrixMarket writes m as a MatrixMarket coordinate file, real general,
+// omitting explicit zero entries.
+func saveMatrixMarket(path string, m mat.Matrix) error {
+	rows, cols := m.Dims()
+
+	type entry struct {
+		i, j int
+		v    float64
+	}
+	var entries []entry
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if v := m.At(i, j); v != 0 {
+				entries = append(entries, entry{i, j, v})
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("%%MatrixMarket matrix coordinate real general\n")
+	fmt.Fprintf(&sb, "%d %d %d\n", rows, cols, len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "%d %d %v\n", e.i+1, e.j+1, e.v)
+	}
+
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
 // isVector checks if the provided matrix is a vector (either a row or a column vector)
 func isVector(matrix mat.Matrix) bool {
 	r, c := matrix.Dims()
 	return r == 1 || c == 1
 }
 
-// calculateNullspace computes the nullspace of a matrix using SVD
+// operation describes a single named action reachable from a selection menu.
+// Handlers receive the current model and return its updated state, which
+// keeps the select/selectOp/selectDecomp dispatch declarative instead of
+// growing into another giant switch every time an operation is added.
+type operation struct {
+	name    string
+	label   string
+	handler func(m model) model
+}
+
+// menu renders an operation table as the "name (Label), ..." hint shown in
+// the corresponding view.
+func menu(ops []operation) string {
+	parts := make([]string, len(ops))
+	for i, op := range ops {
+		parts[i] = fmt.Sprintf("%s (%s)", op.name, op.label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// dispatch runs the handler for m.input in ops, or reports an invalid
+// selection and stays in invalidState.
+func dispatch(m model, ops []operation, invalidState string) model {
+	for _, op := range ops {
+		if op.name == m.input {
+			m.lastResult = nil
+			return op.handler(m)
+		}
+	}
+	m.input = fmt.Sprintf("Invalid selection. Please choose one of: %s.\n", menu(ops))
+	m.state = invalidState
+	return m
+}
+
+// selectOps are the operations available once matrix A has been entered.
+var selectOps = []operation{
+	{"det", "Determinant", opDet},
+	{"norm", "Norm", opNorm},
+	{"nullspace", "Nullspace", opNullspace},
+	{"info", "Matrix Info", opInfo},
+	{"inner", "Inner Product", opNeedsB},
+	{"outer", "Outer Product", opNeedsB},
+	{"multiply", "Matrix Multiplication", opNeedsB},
+	{"solve", "Solve A*x=b", opNeedsB},
+	{"decompose", "Matrix Decomposition", opDecompose},
+	{"pinv", "Pseudo-Inverse", opNeedsParams},
+}
+
+// paramOps are operations that need extra parameters from the user, entered
+// as a single comma-separated line in the inputParams state.
+var paramOps = map[string]func(m model, params string) model{
+	"pinv":  opPinv,
+	"cg":    opCG,
+	"gmres": opGMRES,
+}
+
+// paramHints gives a short description of the parameters expected in the
+// inputParams state for each operation in paramOps.
+var paramHints = map[string]string{
+	"pinv":  "a tolerance (e.g. 1e-10)",
+	"cg":    "tol,maxIter (e.g. 1e-8,1000)",
+	"gmres": "tol,maxIter,restart (e.g. 1e-8,1000,30)",
+}
+
+// twoMatrixOps are the operations available once both A and B have been entered.
+var twoMatrixOps = []operation{
+	{"inner", "Inner Product", opInner},
+	{"outer", "Outer Product", opOuter},
+	{"multiply", "Matrix Multiplication", opMultiply},
+	{"solve", "Solve A*x=b", opSolve},
+	{"cg", "Conjugate Gradient (sparse, SPD)", opNeedsParams},
+	{"gmres", "GMRES (sparse)", opNeedsParams},
+}
+
+// decompositionOps are the factorizations reachable from the decompose menu.
+var decompositionOps = []operation{
+	{"lu", "LU", opDecomposeLU},
+	{"qr", "QR", opDecomposeQR},
+	{"chol", "Cholesky", opDecomposeCholesky},
+	{"svd", "SVD", opDecomposeSVD},
+	{"eig", "Eigendecomposition", opDecomposeEig},
+}
+
+func opDet(m model) model {
+	if isVector(m.matrixA) {
+		m.input = "Determinant is only defined for matrices."
+	} else {
+		det := mat.Det(m.matrixA)
+		m.input = fmt.Sprintf("Determinant: %v", det)
+	}
+	m.state = "result"
+	return m
+}
+
+func opNorm(m model) model {
+	norm := mat.Norm(m.matrixA, 2)
+	m.input = fmt.Sprintf("Norm: %v", norm)
+	m.state = "result"
+	return m
+}
+
+func opNullspace(m model) model {
+	nullspace, err := calculateNullspace(m.matrixA)
+	if err != nil {
+		m.input = fmt.Sprintf("Error calculating nullspace: %v", err)
+	} else {
+		m.input = fmt.Sprintf("Nullspace:\n%v", matrixToString(nullspace))
+		m.lastResult = nullspace
+	}
+	m.state = "result"
+	return m
+}
+
+// opInfo reports structural and numerical properties of matrix A, giving the
+// user context before choosing an operation.
+func opInfo(m model) model {
+	m.input = matrixInfo(m.matrixA)
+	m.state = "result"
+	return m
+}
+
+// opNeedsB transitions to the inputB state so a second matrix/vector can be
+// entered before the operation in twoMatrixOps actually runs.
+func opNeedsB(m model) model {
+	m.input = ""
+	m.state = "inputB"
+	return m
+}
+
+// opDecompose transitions to the decomposition-picker state.
+func opDecompose(m model) model {
+	m.input = ""
+	m.state = "selectDecomp"
+	return m
+}
+
+// opNeedsParams transitions to the inputParams state so extra parameters can
+// be entered before the operation in paramOps actually runs.
+func opNeedsParams(m model) model {
+	m.pendingOp = m.input
+	m.input = ""
+	m.state = "inputParams"
+	return m
+}
+
+func opPinv(m model, params string) model {
+	tol, err := strconv.ParseFloat(params, 64)
+	if err != nil {
+		m.err = fmt.Errorf("invalid tolerance: %v", err)
+		m.state = "error"
+		return m
+	}
+
+	pinv, err := pseudoInverse(m.matrixA, tol)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing pseudo-inverse: %v", err)
+	} else {
+		m.input = fmt.Sprintf("Pseudo-Inverse:\n%s", matrixToString(pinv))
+		m.lastResult = pinv
+	}
+	m.state = "result"
+	return m
+}
+
+func opInner(m model) model {
+	inner, err := innerProduct(m.matrixA, m.matrixB)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing inner product: %v", err)
+	} else {
+		m.input = fmt.Sprintf("Inner Product: %v", inner)
+	}
+	m.state = "result"
+	return m
+}
+
+func opOuter(m model) model {
+	outer, err := outerProduct(m.matrixA, m.matrixB)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing outer product: %v", err)
+	} else {
+		m.input = fmt.Sprintf("Outer Product:\n%v", matrixToString(outer))
+		m.lastResult = outer
+	}
+	m.state = "result"
+	return m
+}
+
+func opMultiply(m model) model {
+	if !isVector(m.matrixA) && !isVector(m.matrixB) {
+		rA, cA := m.matrixA.Dims()
+		rB, cB := m.matrixB.Dims()
+		if cA != rB {
+			m.input = "Matrices are not compatible for multiplication."
+		} else {
+			product := mat.NewDense(rA, cB, nil)
+			product.Mul(m.matrixA, m.matrixB)
+			m.input = fmt.Sprintf("Matrix Product:\n%v", matrixToString(product))
+			m.lastResult = product
+		}
+	} else {
+		m.input = "Matrix multiplication is only defined for matrices."
+	}
+	m.state = "result"
+	return m
+}
+
+func opSolve(m model) model {
+	if isVector(m.matrixA) {
+		m.input = "Solve requires A to be a square matrix."
+	} else if !isVector(m.matrixB) {
+		m.input = "Solve requires b to be a vector."
+	} else {
+		x, cond, err := solveSystem(m.matrixA, m.matrixB)
+		if err != nil {
+			m.input = fmt.Sprintf("Error solving system: %v", err)
+		} else {
+			m.input = fmt.Sprintf("Solution x:\n%sCondition number: %v", matrixToString(x), cond)
+			if cond > solveConditionTolerance {
+				m.input += fmt.Sprintf("\nWarning: A is ill-conditioned (condition number exceeds %v); solution may be inaccurate.", solveConditionTolerance)
+			}
+			m.lastResult = x
+		}
+	}
+	m.state = "result"
+	return m
+}
+
+// parseParams splits a comma-separated parameter string into n float64
+// values, used by operations that need more than one numeric input.
+func parseParams(raw string, n int) ([]float64, error) {
+	fields := strings.Split(raw, ",")
+	if len(fields) != n {
+		return nil, fmt.Errorf("expected %d comma-separated values, got %d", n, len(fields))
+	}
+	values := make([]float64, n)
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q: %v", f, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// vectorOf converts a row- or column-shaped matrix into a dense vector, for
+// use with the iterative solvers which operate on mat.VecDense.
+func vectorOf(m mat.Matrix) (*mat.VecDense, error) {
+	if !isVector(m) {
+		r, c := m.Dims()
+		return nil, fmt.Errorf("expected a vector, got a %dx%d matrix", r, c)
+	}
+	r, c := m.Dims()
+	data := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data = append(data, m.At(i, j))
+		}
+	}
+	return mat.NewVecDense(len(data), data), nil
+}
+
+func opCG(m model, params string) model {
+	values, err := parseParams(params, 2)
+	if err != nil {
+		m.err = fmt.Errorf("invalid CG parameters: %v", err)
+		m.state = "error"
+		return m
+	}
+	tol, maxIter := values[0], int(values[1])
+
+	csr, ok := m.matrixA.(*csrMatrix)
+	if !ok {
+		m.input = "CG requires A to be entered in sparse mode (e.g. 'sparse 1,1,4; 1,2,1; 2,1,1; 2,2,3')."
+		m.state = "result"
+		return m
+	}
+	b, err := vectorOf(m.matrixB)
+	if err != nil {
+		m.input = fmt.Sprintf("Error reading b: %v", err)
+		m.state = "result"
+		return m
+	}
+
+	x, iters, relResidual, err := conjugateGradient(csr, b, tol, maxIter)
+	if err != nil {
+		m.input = fmt.Sprintf("CG error: %v", err)
+	} else {
+		m.input = fmt.Sprintf("Solution x:\n%sIterations: %d\nRelative residual: %v", matrixToString(x), iters, relResidual)
+		m.lastResult = x
+	}
+	m.state = "result"
+	return m
+}
+
+func opGMRES(m model, params string) model {
+	values, err := parseParams(params, 3)
+	if err != nil {
+		m.err = fmt.Errorf("invalid GMRES parameters: %v", err)
+		m.state = "error"
+		return m
+	}
+	tol, maxIter, restart := values[0], int(values[1]), int(values[2])
+
+	csr, ok := m.matrixA.(*csrMatrix)
+	if !ok {
+		m.input = "GMRES requires A to be entered in sparse mode (e.g. 'sparse 1,1,4; 1,2,1; 2,1,1; 2,2,3')."
+		m.state = "result"
+		return m
+	}
+	b, err := vectorOf(m.matrixB)
+	if err != nil {
+		m.input = fmt.Sprintf("Error reading b: %v", err)
+		m.state = "result"
+		return m
+	}
+
+	x, iters, relResidual, err := gmres(csr, b, tol, maxIter, restart)
+	if err != nil {
+		m.input = fmt.Sprintf("GMRES error: %v", err)
+	} else {
+		m.input = fmt.Sprintf("Solution x:\n%sIterations: %d\nRelative residual: %v", matrixToString(x), iters, relResidual)
+		m.lastResult = x
+	}
+	m.state = "result"
+	return m
+}
+
+func opDecomposeLU(m model) model {
+	out, err := decomposeLU(m.matrixA)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing LU decomposition: %v", err)
+	} else {
+		m.input = out
+	}
+	m.state = "result"
+	return m
+}
+
+func opDecomposeQR(m model) model {
+	out, err := decomposeQR(m.matrixA)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing QR decomposition: %v", err)
+	} else {
+		m.input = out
+	}
+	m.state = "result"
+	return m
+}
+
+func opDecomposeCholesky(m model) model {
+	out, err := decomposeCholesky(m.matrixA)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing Cholesky decomposition: %v", err)
+	} else {
+		m.input = out
+	}
+	m.state = "result"
+	return m
+}
+
+func opDecomposeSVD(m model) model {
+	out, err := decomposeSVD(m.matrixA)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing SVD: %v", err)
+	} else {
+		m.input = out
+	}
+	m.state = "result"
+	return m
+}
+
+func opDecomposeEig(m model) model {
+	out, err := decomposeEig(m.matrixA)
+	if err != nil {
+		m.input = fmt.Sprintf("Error computing eigendecomposition: %v", err)
+	} else {
+		m.input = out
+	}
+	m.state = "result"
+	return m
+}
+
+// solveConditionTolerance is the threshold above which solveSystem warns that
+// the matrix is ill-conditioned and the computed solution may be inaccurate.
+const solveConditionTolerance = 1e12
+
+// solveSystem solves A*x = b for x via an LU factorization of A, also
+// returning A's condition number so the caller can warn on ill-conditioning.
+func solveSystem(a, b mat.Matrix) (x *mat.Dense, cond float64, err error) {
+	rA, cA := a.Dims()
+	if rA != cA {
+		return nil, 0, fmt.Errorf("solve requires a square matrix A, got %dx%d", rA, cA)
+	}
+	rB, cB := b.Dims()
+	if rB != rA {
+		return nil, 0, fmt.Errorf("b has %d rows, expected %d to match A", rB, rA)
+	}
+
+	var lu mat.LU
+	lu.Factorize(a)
+
+	cond = lu.Cond()
+	if math.IsInf(cond, 1) {
+		return nil, cond, fmt.Errorf("A is singular, the system has no unique solution")
+	}
+
+	x = mat.NewDense(rA, cB, nil)
+	if err := lu.SolveTo(x, false, b); err != nil {
+		// A Condition error just means the solve is ill-conditioned; the
+		// solution was still computed, so surface it via cond instead of
+		// failing outright.
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, cond, err
+		}
+	}
+
+	return x, cond, nil
+}
+
+// csrMatrix is a compressed sparse row matrix. It implements mat.Matrix so
+// it can be displayed and used like any other matrix in this package, but
+// its real purpose is to back the iterative cg/gmres solvers, which operate
+// on it directly instead of densifying it.
+type csrMatrix struct {
+	rows, cols int
+	rowPtr     []int
+	colIdx     []int
+	values     []float64
+}
+
+func (m *csrMatrix) Dims() (int, int) { return m.rows, m.cols }
+
+func (m *csrMatrix) At(i, j int) float64 {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic("csrMatrix: index out of range")
+	}
+	for k := m.rowPtr[i]; k < m.rowPtr[i+1]; k++ {
+		if m.colIdx[k] == j {
+			return m.values[k]
+		}
+	}
+	return 0
+}
+
+func (m *csrMatrix) T() mat.Matrix { return mat.Transpose{Matrix: m} }
+
+// mulVec computes A*x, using the sparse row structure directly rather than
+// the dense O(rows*cols) path a generic mat.Matrix multiply would take.
+func (m *csrMatrix) mulVec(x *mat.VecDense) *mat.VecDense {
+	y := mat.NewVecDense(m.rows, nil)
+	for i := 0; i < m.rows; i++ {
+		var sum float64
+		for k := m.rowPtr[i]; k < m.rowPtr[i+1]; k++ {
+			sum += m.values[k] * x.AtVec(m.colIdx[k])
+		}
+		y.SetVec(i, sum)
+	}
+	return y
+}
+
+// parseSparseTriplets parses "row,col,value; row,col,value; ..." input
+// (rows and columns 1-indexed, matching the MatrixMarket coordinate
+// convention used elsewhere in this package) into a CSR matrix.
+func parseSparseTriplets(input string) (*csrMatrix, error) {
+	type triplet struct {
+		row, col int
+		value    float64
+	}
+
+	var triplets []triplet
+	rows, cols := 0, 0
+	for _, entry := range strings.Split(input, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid triplet %q: expected row,col,value", entry)
+		}
+		row, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid row in %q: %v", entry, err)
+		}
+		col, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid column in %q: %v", entry, err)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in %q: %v", entry, err)
+		}
+		if row < 1 || col < 1 {
+			return nil, fmt.Errorf("invalid triplet %q: row and column are 1-indexed, got row=%d, col=%d", entry, row, col)
+		}
+		triplets = append(triplets, triplet{row, col, value})
+		if row > rows {
+			rows = row
+		}
+		if col > cols {
+			cols = col
+		}
+	}
+	if len(triplets) == 0 {
+		return nil, fmt.Errorf("no triplets provided")
+	}
+
+	sort.Slice(triplets, func(i, j int) bool {
+		if triplets[i].row != triplets[j].row {
+			return triplets[i].row < triplets[j].row
+		}
+		return triplets[i].col < triplets[j].col
+	})
+
+	for i := 1; i < len(triplets); i++ {
+		if triplets[i].row == triplets[i-1].row && triplets[i].col == triplets[i-1].col {
+			return nil, fmt.Errorf("duplicate triplet for row=%d, col=%d", triplets[i].row, triplets[i].col)
+		}
+	}
+
+	counts := make([]int, rows)
+	for _, t := range triplets {
+		counts[t.row-1]++
+	}
+	rowPtr := make([]int, rows+1)
+	for r := 0; r < rows; r++ {
+		rowPtr[r+1] = rowPtr[r] + counts[r]
+	}
+
+	colIdx := make([]int, len(triplets))
+	values := make([]float64, len(triplets))
+	for i, t := range triplets {
+		colIdx[i] = t.col - 1
+		values[i] = t.value
+	}
+
+	return &csrMatrix{rows: rows, cols: cols, rowPtr: rowPtr, colIdx: colIdx, values: values}, nil
+}
+
+// conjugateGradient solves A*x = b for symmetric positive-definite A using
+// the Conjugate Gradient method, starting from x0 = 0 and stopping once the
+// relative residual drops below tol or maxIter is reached.
+func conjugateGradient(a *csrMatrix, b *mat.VecDense, tol float64, maxIter int) (x *mat.VecDense, iters int, relResidual float64, err error) {
+	n, cols := a.Dims()
+	if n != cols {
+		return nil, 0, 0, fmt.Errorf("CG requires a square matrix, got %dx%d", n, cols)
+	}
+	if b.Len() != n {
+		return nil, 0, 0, fmt.Errorf("b has length %d, expected %d to match A", b.Len(), n)
+	}
+
+	bNorm := mat.Norm(b, 2)
+	if bNorm == 0 {
+		return mat.NewVecDense(n, nil), 0, 0, nil
+	}
+
+	x = mat.NewVecDense(n, nil)
+	r := mat.NewVecDense(n, nil)
+	r.CopyVec(b) // r0 = b - A*x0 = b, since x0 = 0
+	p := mat.NewVecDense(n, nil)
+	p.CopyVec(r)
+
+	rsOld := mat.Dot(r, r)
+
+	for iters = 0; iters < maxIter; iters++ {
+		relResidual = math.Sqrt(rsOld) / bNorm
+		if relResidual < tol {
+			return x, iters, relResidual, nil
+		}
+
+		ap := a.mulVec(p)
+		alpha := rsOld / mat.Dot(p, ap)
+
+		x.AddScaledVec(x, alpha, p)
+		r.AddScaledVec(r, -alpha, ap)
+
+		rsNew := mat.Dot(r, r)
+		beta := rsNew / rsOld
+		p.AddScaledVec(r, beta, p)
+		rsOld = rsNew
+	}
+
+	relResidual = math.Sqrt(rsOld) / bNorm
+	return x, iters, relResidual, fmt.Errorf("did not converge within %d iterations (relative residual %v)", maxIter, relResidual)
+}
+
+// gmres solves A*x = b for general A using restarted GMRES(restart),
+// solving the Hessenberg least-squares problem at each cycle via Givens
+// rotations and restarting from the updated x until tol or maxIter is hit.
+func gmres(a *csrMatrix, b *mat.VecDense, tol float64, maxIter, restart int) (x *mat.VecDense, iters int, relResidual float64, err error) {
+	n, cols := a.Dims()
+	if n != cols {
+		return nil, 0, 0, fmt.Errorf("GMRES requires a square matrix, got %dx%d", n, cols)
+	}
+	if b.Len() != n {
+		return nil, 0, 0, fmt.Errorf("b has length %d, expected %d to match A", b.Len(), n)
+	}
+	if restart < 1 {
+		return nil, 0, 0, fmt.Errorf("restart parameter must be at least 1, got %d", restart)
+	}
+
+	bNorm := mat.Norm(b, 2)
+	if bNorm == 0 {
+		return mat.NewVecDense(n, nil), 0, 0, nil
+	}
+
+	x = mat.NewVecDense(n, nil)
+
+	for iters < maxIter {
+		r := mat.NewVecDense(n, nil)
+		r.SubVec(b, a.mulVec(x))
+		beta := mat.Norm(r, 2)
+		relResidual = beta / bNorm
+		if relResidual < tol {
+			return x, iters, relResidual, nil
+		}
+
+		v := make([]*mat.VecDense, restart+1)
+		v[0] = mat.NewVecDense(n, nil)
+		v[0].ScaleVec(1/beta, r)
+
+		h := make([][]float64, restart+1)
+		for i := range h {
+			h[i] = make([]float64, restart)
+		}
+		cs := make([]float64, restart)
+		sn := make([]float64, restart)
+		g := make([]float64, restart+1)
+		g[0] = beta
+
+		k := 0
+		for ; k < restart && iters < maxIter; k++ {
+			iters++
+			w := a.mulVec(v[k])
+			for i := 0; i <= k; i++ {
+				h[i][k] = mat.Dot(w, v[i])
+				w.AddScaledVec(w, -h[i][k], v[i])
+			}
+			h[k+1][k] = mat.Norm(w, 2)
+			breakdown := h[k+1][k] <= 1e-14
+			if !breakdown {
+				v[k+1] = mat.NewVecDense(n, nil)
+				v[k+1].ScaleVec(1/h[k+1][k], w)
+			}
+
+			// Apply the previously accumulated Givens rotations to the new column of H
+			for i := 0; i < k; i++ {
+				hik, hi1k := h[i][k], h[i+1][k]
+				h[i][k] = cs[i]*hik + sn[i]*hi1k
+				h[i+1][k] = -sn[i]*hik + cs[i]*hi1k
+			}
+
+			// Compute and apply a new Givens rotation to zero out h[k+1][k]
+			denom := math.Hypot(h[k][k], h[k+1][k])
+			cs[k] = h[k][k] / denom
+			sn[k] = h[k+1][k] / denom
+			h[k][k] = cs[k]*h[k][k] + sn[k]*h[k+1][k]
+			h[k+1][k] = 0
+
+			g[k+1] = -sn[k] * g[k]
+			g[k] = cs[k] * g[k]
+
+			relResidual = math.Abs(g[k+1]) / bNorm
+			if relResidual < tol || breakdown {
+				// A breakdown means the Krylov subspace is exhausted and
+				// v[k+1] was left nil; stop now instead of using it on the
+				// next iteration.
+				k++
+				break
+			}
+		}
+
+		// Back-substitute to solve the upper-triangular system H*y = g for
+		// the k Krylov directions computed this cycle.
+		y := make([]float64, k)
+		for i := k - 1; i >= 0; i-- {
+			sum := g[i]
+			for j := i + 1; j < k; j++ {
+				sum -= h[i][j] * y[j]
+			}
+			y[i] = sum / h[i][i]
+		}
+		for i := 0; i < k; i++ {
+			x.AddScaledVec(x, y[i], v[i])
+		}
+
+		if relResidual < tol {
+			return x, iters, relResidual, nil
+		}
+	}
+
+	return x, iters, relResidual, fmt.Errorf("did not converge within %d iterations (relative residual %v)", maxIter, relResidual)
+}
+
+// isSymmetric reports whether a equals its own transpose to within a small tolerance.
+func isSymmetric(a mat.Matrix) bool {
+	r, c := a.Dims()
+	if r != c {
+		return false
+	}
+	for i := 0; i < r; i++ {
+		for j := i + 1; j < c; j++ {
+			if math.Abs(a.At(i, j)-a.At(j, i)) > 1e-9 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matrixRank computes the numerical rank of a via SVD, along with its
+// largest and smallest singular values. Singular values no larger than
+// max(rows,cols)*eps*sigmaMax are treated as zero, following the same
+// convention used by MATLAB and numpy's rank().
+func matrixRank(a mat.Matrix) (rank int, sigmaMax, sigmaMin float64) {
+	rows, cols := a.Dims()
+
+	var svd mat.SVD
+	if ok := svd.Factorize(a, mat.SVDNone); !ok {
+		return 0, 0, 0
+	}
+	s := svd.Values(nil)
+	if len(s) == 0 {
+		return 0, 0, 0
+	}
+
+	sigmaMax = s[0]
+	sigmaMin = s[len(s)-1]
+	threshold := float64(max(rows, cols)) * 2.220446049250313e-16 * sigmaMax
+	for _, sv := range s {
+		if sv > threshold {
+			rank++
+		}
+	}
+	return rank, sigmaMax, sigmaMin
+}
+
+// matrixTrace returns the sum of the diagonal entries of a square matrix a.
+func matrixTrace(a mat.Matrix) float64 {
+	r, c := a.Dims()
+	n := r
+	if c < n {
+		n = c
+	}
+	var trace float64
+	for i := 0; i < n; i++ {
+		trace += a.At(i, i)
+	}
+	return trace
+}
+
+// isPositiveDefinite reports whether a is symmetric positive definite, by
+// attempting a Cholesky factorization.
+func isPositiveDefinite(a mat.Matrix) bool {
+	r, c := a.Dims()
+	if r != c || !isSymmetric(a) {
+		return false
+	}
+
+	data := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data[i*c+j] = a.At(i, j)
+		}
+	}
+	sym := mat.NewSymDense(r, data)
+
+	var chol mat.Cholesky
+	return chol.Factorize(sym)
+}
+
+// isOrthogonal reports whether a is square and satisfies A^T*A = I to within
+// a small tolerance.
+func isOrthogonal(a mat.Matrix) bool {
+	r, c := a.Dims()
+	if r != c {
+		return false
+	}
+
+	var ata mat.Dense
+	ata.Mul(a.T(), a)
+	for i := 0; i < c; i++ {
+		for j := 0; j < c; j++ {
+			want := 0.0
+			if i == j {
+				want = 1.0
+			}
+			if math.Abs(ata.At(i, j)-want) > 1e-9 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isDiagonallyDominant reports whether a is square and, for every row, the
+// absolute value of the diagonal entry is at least the sum of the absolute
+// values of the other entries in that row.
+func isDiagonallyDominant(a mat.Matrix) bool {
+	r, c := a.Dims()
+	if r != c {
+		return false
+	}
+
+	for i := 0; i < r; i++ {
+		var offDiagSum float64
+		for j := 0; j < c; j++ {
+			if j != i {
+				offDiagSum += math.Abs(a.At(i, j))
+			}
+		}
+		if math.Abs(a.At(i, i)) < offDiagSum {
+			return false
+		}
+	}
+	return true
+}
+
+// matrixInfo formats shape, rank, norms, trace, condition number, and
+// structural property flags for a as a two-column table.
+func matrixInfo(a mat.Matrix) string {
+	rows, cols := a.Dims()
+	rank, sigmaMax, sigmaMin := matrixRank(a)
+	cond := math.Inf(1)
+	if sigmaMin > 0 {
+		cond = sigmaMax / sigmaMin
+	}
+
+	rows2 := [][2]string{
+		{"Shape", fmt.Sprintf("%dx%d", rows, cols)},
+		{"Rank", fmt.Sprintf("%d", rank)},
+		{"1-Norm", fmt.Sprintf("%v", mat.Norm(a, 1))},
+		{"2-Norm", fmt.Sprintf("%v", sigmaMax)},
+		{"Inf-Norm", fmt.Sprintf("%v", mat.Norm(a, math.Inf(1)))},
+		{"Frobenius Norm", fmt.Sprintf("%v", mat.Norm(a, 2))},
+		{"Trace", fmt.Sprintf("%v", matrixTrace(a))},
+		{"Condition Number", fmt.Sprintf("%v", cond)},
+		{"Symmetric", fmt.Sprintf("%v", isSymmetric(a))},
+		{"Positive Definite", fmt.Sprintf("%v", isPositiveDefinite(a))},
+		{"Orthogonal", fmt.Sprintf("%v", isOrthogonal(a))},
+		{"Diagonally Dominant", fmt.Sprintf("%v", isDiagonallyDominant(a))},
+	}
+
+	labelWidth := 0
+	for _, row := range rows2 {
+		if len(row[0]) > labelWidth {
+			labelWidth = len(row[0])
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Matrix Info:\n")
+	for _, row := range rows2 {
+		fmt.Fprintf(&sb, "%-*s  %s\n", labelWidth, row[0], row[1])
+	}
+	return sb.String()
+}
+
+// complexMatrixToString formats a complex matrix for display, mirroring
+// matrixToString for the real-valued case.
+func complexMatrixToString(m mat.CMatrix) string {
+	r, c := m.Dims()
+	var sb strings.Builder
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			fmt.Fprintf(&sb, "%v ", m.At(i, j))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// decomposeLU computes the LU factorization of a and formats its L, U, and
+// pivot factors for display.
+func decomposeLU(a mat.Matrix) (string, error) {
+	r, c := a.Dims()
+	if r != c {
+		return "", fmt.Errorf("LU decomposition requires a square matrix, got %dx%d", r, c)
+	}
+
+	var lu mat.LU
+	lu.Factorize(a)
+
+	var l, u mat.TriDense
+	lu.LTo(&l)
+	lu.UTo(&u)
+	pivots := lu.Pivot(nil)
+
+	return fmt.Sprintf("L:\n%sU:\n%sPivots: %v", matrixToString(&l), matrixToString(&u), pivots), nil
+}
+
+// decomposeQR computes the QR factorization of a and formats its Q and R
+// factors for display. QR requires at least as many rows as columns.
+func decomposeQR(a mat.Matrix) (string, error) {
+	r, c := a.Dims()
+	if r < c {
+		return "", fmt.Errorf("QR decomposition requires rows >= columns, got %dx%d", r, c)
+	}
+
+	var qr mat.QR
+	qr.Factorize(a)
+
+	var q, rFactor mat.Dense
+	qr.QTo(&q)
+	qr.RTo(&rFactor)
+
+	return fmt.Sprintf("Q:\n%sR:\n%s", matrixToString(&q), matrixToString(&rFactor)), nil
+}
+
+// decomposeCholesky computes the Cholesky factorization of a and formats its
+// U factor for display. Cholesky requires a is square, symmetric, and
+// positive definite.
+func decomposeCholesky(a mat.Matrix) (string, error) {
+	r, c := a.Dims()
+	if r != c {
+		return "", fmt.Errorf("Cholesky decomposition requires a square matrix, got %dx%d", r, c)
+	}
+	if !isSymmetric(a) {
+		return "", fmt.Errorf("Cholesky decomposition requires a symmetric matrix")
+	}
+
+	data := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data[i*c+j] = a.At(i, j)
+		}
+	}
+	sym := mat.NewSymDense(r, data)
+
+	var chol mat.Cholesky
+	if ok := chol.Factorize(sym); !ok {
+		return "", fmt.Errorf("matrix is not positive definite")
+	}
+
+	var u mat.TriDense
+	chol.UTo(&u)
+
+	return fmt.Sprintf("U (A = U^T*U):\n%s", matrixToString(&u)), nil
+}
+
+// decomposeSVD computes the singular value decomposition of a and formats
+// its U, singular values, and V factors for display.
+func decomposeSVD(a mat.Matrix) (string, error) {
+	var svd mat.SVD
+	if ok := svd.Factorize(a, mat.SVDThin); !ok {
+		return "", fmt.Errorf("SVD factorization failed")
+	}
+
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	s := svd.Values(nil)
+
+	return fmt.Sprintf("U:\n%sSingular values: %v\nV:\n%s", matrixToString(&u), s, matrixToString(&v)), nil
+}
+
+// decomposeEig computes the eigendecomposition of a and formats its
+// eigenvalues and eigenvectors for display. Eigenvalues and eigenvectors may
+// be complex even for a real matrix.
+func decomposeEig(a mat.Matrix) (string, error) {
+	r, c := a.Dims()
+	if r != c {
+		return "", fmt.Errorf("eigendecomposition requires a square matrix, got %dx%d", r, c)
+	}
+
+	var eig mat.Eigen
+	if ok := eig.Factorize(a, mat.EigenBoth); !ok {
+		return "", fmt.Errorf("eigendecomposition failed to converge")
+	}
+
+	values := eig.Values(nil)
+	var vectors mat.CDense
+	eig.VectorsTo(&vectors)
+
+	return fmt.Sprintf("Eigenvalues: %v\nEigenvectors:\n%s", values, complexMatrixToString(&vectors)), nil
+}
+
+// calculateNullspace computes the nullspace of a matrix using SVD. Since
+// A = U*S*V^T, the columns of V paired with (near-)zero singular values
+// (including the implicit zero singular values when A has more columns
+// than rows) satisfy A*v = 0 and so span the nullspace.
 func calculateNullspace(matrix mat.Matrix) (mat.Matrix, error) {
 	var svd mat.SVD
-	ok := svd.Factorize(matrix, mat.SVDThin)
+	ok := svd.Factorize(matrix, mat.SVDFull)
 	if !ok {
 		return nil, fmt.Errorf("SVD factorization failed")
 	}
 
-	rows, cols := matrix.Dims()
+	_, cols := matrix.Dims()
 
-	// Correctly initialize U matrix to store left singular vectors
-	u := mat.NewDense(rows, rows, nil)
-	svd.UTo(u)
+	v := mat.NewDense(cols, cols, nil)
+	svd.VTo(v)
 
 	s := svd.Values(nil)
 
-	// Identify columns in U corresponding to near-zero singular values
+	// Identify columns of V corresponding to near-zero (or implicit) singular values
 	var nullspaceCols []int
-	for i, singularValue := range s {
-		if singularValue <= 1e-12 {
-			nullspaceCols = append(nullspaceCols, i)
+	for j := 0; j < cols; j++ {
+		if j >= len(s) || s[j] <= 1e-12 {
+			nullspaceCols = append(nullspaceCols, j)
 		}
 	}
 
@@ -233,60 +1443,89 @@ func calculateNullspace(matrix mat.Matrix) (mat.Matrix, error) {
 	}
 
 	// Construct the nullspace matrix
-	nullspace := mat.NewDense(rows, len(nullspaceCols), nil)
+	nullspace := mat.NewDense(cols, len(nullspaceCols), nil)
 	for j, colIndex := range nullspaceCols {
-		for i := 0; i < rows; i++ {
-			nullspace.Set(i, j, u.At(i, colIndex))
+		for i := 0; i < cols; i++ {
+			nullspace.Set(i, j, v.At(i, colIndex))
 		}
 	}
 
 	return nullspace, nil
 }
 
-// outerProduct computes the outer product of two vectors
-func outerProduct(a, b *mat.Dense) *mat.Dense {
-	rA, cA := a.Dims()
-	rB, cB := b.Dims()
+// pseudoInverse computes the Moore-Penrose pseudo-inverse of m via its SVD,
+// A = U*S*V^T. Singular values no larger than tol*sigmaMax*max(rows,cols)
+// are treated as zero when forming S^+, so the result is well-defined for
+// rectangular and rank-deficient matrices.
+func pseudoInverse(m mat.Matrix, tol float64) (*mat.Dense, error) {
+	rows, cols := m.Dims()
+
+	var svd mat.SVD
+	if ok := svd.Factorize(m, mat.SVDFull); !ok {
+		return nil, fmt.Errorf("SVD factorization failed")
+	}
+
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	s := svd.Values(nil)
 
-	if cA != 1 && rA != 1 {
-		panic("First input is not a vector")
+	sigmaMax := 0.0
+	for _, sv := range s {
+		if sv > sigmaMax {
+			sigmaMax = sv
+		}
 	}
+	threshold := tol * sigmaMax * float64(max(rows, cols))
 
-	if cB != 1 && rB != 1 {
-		panic("Second input is not a vector")
+	sPlus := mat.NewDense(cols, rows, nil)
+	for i, sv := range s {
+		if sv > threshold {
+			sPlus.Set(i, i, 1/sv)
+		}
 	}
 
-	outer := mat.NewDense(rA*rB, cA*cB, nil)
-	outer.Mul(a, b.T())
-	return outer
+	var vSPlus, result mat.Dense
+	vSPlus.Mul(&v, sPlus)
+	result.Mul(&vSPlus, u.T())
+
+	return &result, nil
 }
 
-// innerProduct computers the inner product of two vectors
-func innerProduct(a, b *mat.Dense) (float64, error) {
-	rA, cA := a.Dims()
-	rB, cB := b.Dims()
+// outerProduct computes the outer product of two vectors through the
+// mat.Matrix interface via vectorOf, so it works for sparse as well as
+// dense operands.
+func outerProduct(a, b mat.Matrix) (*mat.Dense, error) {
+	av, err := vectorOf(a)
+	if err != nil {
+		return nil, err
+	}
+	bv, err := vectorOf(b)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if both inputs are vectors
-	if !(isVector(a) && isVector(b)) {
-		return 0, fmt.Errorf("Inner product is only defined for vectors.")
-	}
-
-	// Convert to 1D slices and compute dot product
-	if rA == 1 && cB == 1 {
-		// a is a row vector and b is a column vector
-		return mat.Dot(a.RowView(0), b.ColView(0)), nil
-	} else if cA == 1 && rB == 1 {
-		// a is a column vector and b is a row vector
-		return mat.Dot(a.ColView(0), b.RowView(0)), nil
-	} else if rA == 1 && rB == 1 && cA == cB {
-		// Both a and b are row vectors of the same length
-		return mat.Dot(a.RowView(0), b.RowView(0)), nil
-	} else if cA == 1 && cB == 1 && rA == rB {
-		// Both a and b are column vectors of the same length
-		return mat.Dot(a.ColView(0), b.ColView(0)), nil
-	} else {
-		return 0, fmt.Errorf("Vectors must have the same dimension.")
+	outer := mat.NewDense(av.Len(), bv.Len(), nil)
+	outer.Outer(1, av, bv)
+	return outer, nil
+}
+
+// innerProduct computes the inner product of two vectors through the
+// mat.Matrix interface via vectorOf, so it works for sparse as well as
+// dense operands.
+func innerProduct(a, b mat.Matrix) (float64, error) {
+	av, err := vectorOf(a)
+	if err != nil {
+		return 0, err
+	}
+	bv, err := vectorOf(b)
+	if err != nil {
+		return 0, err
+	}
+	if av.Len() != bv.Len() {
+		return 0, fmt.Errorf("vectors must have the same dimension")
 	}
+	return mat.Dot(av, bv), nil
 }
 
 // main is the entry point of the application
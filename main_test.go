@@ -0,0 +1,302 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TestLoadMatrixMarketCoordinateOutOfBounds ensures a corrupted coordinate
+// entry whose row/column exceeds the declared size returns an error instead
+// of panicking inside mat.Dense.Set.
+func TestLoadMatrixMarketCoordinateOutOfBounds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.mtx")
+	contents := "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 5.0\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	if _, err := loadMatrixMarket(path); err == nil {
+		t.Error("expected an error for an out-of-bounds coordinate entry")
+	}
+}
+
+// TestLoadMatrixMarketArrayOutOfBounds ensures a corrupted array body with
+// more entries than the declared size returns an error instead of panicking.
+func TestLoadMatrixMarketArrayOutOfBounds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.mtx")
+	contents := "%%MatrixMarket matrix array real general\n2 2\n1.0\n2.0\n3.0\n4.0\n5.0\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	if _, err := loadMatrixMarket(path); err == nil {
+		t.Error("expected an error for an array body with too many entries")
+	}
+}
+
+// TestLoadMatrixMarketSymmetricArray ensures a symmetric array body, which
+// stores only the lower triangle in column-major order, is expanded into
+// the correct full matrix rather than shifted into the wrong cells.
+func TestLoadMatrixMarketSymmetricArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sym.mtx")
+	contents := "%%MatrixMarket matrix array real symmetric\n3 3\n1.0\n2.0\n3.0\n4.0\n5.0\n6.0\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	m, err := loadMatrixMarket(path)
+	if err != nil {
+		t.Fatalf("loadMatrixMarket returned error: %v", err)
+	}
+
+	want := mat.NewDense(3, 3, []float64{
+		1, 2, 3,
+		2, 4, 5,
+		3, 5, 6,
+	})
+	r, c := m.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if m.At(i, j) != want.At(i, j) {
+				t.Errorf("m[%d][%d] = %v, want %v", i, j, m.At(i, j), want.At(i, j))
+			}
+		}
+	}
+}
+
+// TestGMRESBreakdownDoesNotPanic exercises a restart value larger than the
+// system's dimension, which forces a Krylov breakdown before restart steps
+// are taken; GMRES must stop cleanly instead of dereferencing a nil basis
+// vector on the next iteration.
+func TestGMRESBreakdownDoesNotPanic(t *testing.T) {
+	csr, err := parseSparseTriplets("1,1,2; 2,2,3")
+	if err != nil {
+		t.Fatalf("parseSparseTriplets returned error: %v", err)
+	}
+	b := mat.NewVecDense(2, []float64{4, 9})
+
+	x, _, _, err := gmres(csr, b, 1e-14, 100, 30)
+	if err != nil {
+		t.Fatalf("gmres returned error: %v", err)
+	}
+	if math.Abs(x.AtVec(0)-2) > 1e-6 || math.Abs(x.AtVec(1)-3) > 1e-6 {
+		t.Errorf("x = [%v %v], want [2 3]", x.AtVec(0), x.AtVec(1))
+	}
+}
+
+func TestIsSymmetric(t *testing.T) {
+	sym := mat.NewDense(2, 2, []float64{1, 2, 2, 3})
+	if !isSymmetric(sym) {
+		t.Error("expected symmetric matrix to be reported as symmetric")
+	}
+
+	notSym := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	if isSymmetric(notSym) {
+		t.Error("expected non-symmetric matrix to be reported as not symmetric")
+	}
+
+	notSquare := mat.NewDense(2, 3, nil)
+	if isSymmetric(notSquare) {
+		t.Error("expected non-square matrix to be reported as not symmetric")
+	}
+}
+
+func TestIsPositiveDefinite(t *testing.T) {
+	identity := mat.NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	})
+	if !isPositiveDefinite(identity) {
+		t.Error("expected identity matrix to be positive definite")
+	}
+
+	notSPD := mat.NewDense(2, 2, []float64{0, 1, 1, 0})
+	if isPositiveDefinite(notSPD) {
+		t.Error("expected non-symmetric matrix to not be positive definite")
+	}
+
+	negDef := mat.NewDense(2, 2, []float64{-1, 0, 0, -1})
+	if isPositiveDefinite(negDef) {
+		t.Error("expected negative definite matrix to not be positive definite")
+	}
+}
+
+func TestIsOrthogonal(t *testing.T) {
+	identity := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	if !isOrthogonal(identity) {
+		t.Error("expected identity matrix to be orthogonal")
+	}
+
+	rotation := mat.NewDense(2, 2, []float64{0, -1, 1, 0})
+	if !isOrthogonal(rotation) {
+		t.Error("expected rotation matrix to be orthogonal")
+	}
+
+	notOrthogonal := mat.NewDense(2, 2, []float64{1, 1, 0, 1})
+	if isOrthogonal(notOrthogonal) {
+		t.Error("expected non-orthogonal matrix to not be orthogonal")
+	}
+}
+
+func TestIsDiagonallyDominant(t *testing.T) {
+	dominant := mat.NewDense(2, 2, []float64{4, 1, 1, 3})
+	if !isDiagonallyDominant(dominant) {
+		t.Error("expected diagonally dominant matrix to be reported as such")
+	}
+
+	notDominant := mat.NewDense(2, 2, []float64{1, 2, 0, 1})
+	if isDiagonallyDominant(notDominant) {
+		t.Error("expected non-diagonally-dominant matrix to not be reported as such")
+	}
+}
+
+func TestMatrixRank(t *testing.T) {
+	identity := mat.NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	})
+	rank, sigmaMax, sigmaMin := matrixRank(identity)
+	if rank != 3 {
+		t.Errorf("rank = %d, want 3", rank)
+	}
+	if math.Abs(sigmaMax-1) > 1e-9 || math.Abs(sigmaMin-1) > 1e-9 {
+		t.Errorf("sigmaMax/sigmaMin = %v/%v, want 1/1", sigmaMax, sigmaMin)
+	}
+
+	singular := mat.NewDense(2, 2, []float64{1, 1, 1, 1})
+	if rank, _, _ := matrixRank(singular); rank != 1 {
+		t.Errorf("rank = %d, want 1", rank)
+	}
+}
+
+func TestMatrixTrace(t *testing.T) {
+	a := mat.NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+	})
+	if trace := matrixTrace(a); trace != 15 {
+		t.Errorf("trace = %v, want 15", trace)
+	}
+}
+
+func TestSolveSystem(t *testing.T) {
+	a := mat.NewDense(2, 2, []float64{2, 0, 0, 3})
+	b := mat.NewDense(2, 1, []float64{4, 9})
+
+	x, _, err := solveSystem(a, b)
+	if err != nil {
+		t.Fatalf("solveSystem returned error: %v", err)
+	}
+	if math.Abs(x.At(0, 0)-2) > 1e-9 || math.Abs(x.At(1, 0)-3) > 1e-9 {
+		t.Errorf("x = [%v %v], want [2 3]", x.At(0, 0), x.At(1, 0))
+	}
+
+	nonSquare := mat.NewDense(2, 3, nil)
+	if _, _, err := solveSystem(nonSquare, b); err == nil {
+		t.Error("expected error for non-square A")
+	}
+}
+
+func TestPseudoInverse(t *testing.T) {
+	a := mat.NewDense(2, 2, []float64{2, 0, 0, 4})
+	pinv, err := pseudoInverse(a, 1e-10)
+	if err != nil {
+		t.Fatalf("pseudoInverse returned error: %v", err)
+	}
+	if math.Abs(pinv.At(0, 0)-0.5) > 1e-9 || math.Abs(pinv.At(1, 1)-0.25) > 1e-9 {
+		t.Errorf("pinv = [%v, %v], want [0.5, 0.25]", pinv.At(0, 0), pinv.At(1, 1))
+	}
+}
+
+func TestConjugateGradient(t *testing.T) {
+	csr, err := parseSparseTriplets("1,1,4; 1,2,1; 2,1,1; 2,2,3")
+	if err != nil {
+		t.Fatalf("parseSparseTriplets returned error: %v", err)
+	}
+	b := mat.NewVecDense(2, []float64{1, 2})
+
+	x, _, relResidual, err := conjugateGradient(csr, b, 1e-10, 100)
+	if err != nil {
+		t.Fatalf("conjugateGradient returned error: %v", err)
+	}
+	if relResidual >= 1e-8 {
+		t.Errorf("relative residual = %v, want < 1e-8", relResidual)
+	}
+	if math.Abs(x.AtVec(0)-0.0909090909) > 1e-6 || math.Abs(x.AtVec(1)-0.6363636364) > 1e-6 {
+		t.Errorf("x = [%v %v], want [0.0909... 0.6363...]", x.AtVec(0), x.AtVec(1))
+	}
+}
+
+func TestGMRES(t *testing.T) {
+	csr, err := parseSparseTriplets("1,1,1; 1,2,2; 2,1,3; 2,2,4")
+	if err != nil {
+		t.Fatalf("parseSparseTriplets returned error: %v", err)
+	}
+	b := mat.NewVecDense(2, []float64{5, 6})
+
+	x, _, relResidual, err := gmres(csr, b, 1e-10, 100, 30)
+	if err != nil {
+		t.Fatalf("gmres returned error: %v", err)
+	}
+	if relResidual >= 1e-8 {
+		t.Errorf("relative residual = %v, want < 1e-8", relResidual)
+	}
+	if math.Abs(x.AtVec(0)-(-4)) > 1e-6 || math.Abs(x.AtVec(1)-4.5) > 1e-6 {
+		t.Errorf("x = [%v %v], want [-4 4.5]", x.AtVec(0), x.AtVec(1))
+	}
+}
+
+// TestParseSparseTripletsRejectsZeroIndex ensures a 0-based row or column
+// (the triplet format is documented as 1-indexed) returns an error instead
+// of panicking on a negative slice index.
+func TestParseSparseTripletsRejectsZeroIndex(t *testing.T) {
+	if _, err := parseSparseTriplets("0,1,5; 1,1,2"); err == nil {
+		t.Error("expected an error for a 0-based row")
+	}
+	if _, err := parseSparseTriplets("1,0,5; 1,1,2"); err == nil {
+		t.Error("expected an error for a 0-based column")
+	}
+}
+
+// TestParseSparseTripletsRejectsDuplicates ensures a repeated (row, col)
+// coordinate is rejected at parse time, since At (used for display) and
+// mulVec (used by CG/GMRES) previously disagreed on how to treat it.
+func TestParseSparseTripletsRejectsDuplicates(t *testing.T) {
+	if _, err := parseSparseTriplets("1,1,2; 1,1,3"); err == nil {
+		t.Error("expected an error for a duplicate triplet")
+	}
+}
+
+// TestInnerOuterProductSparse exercises innerProduct/outerProduct with a
+// *csrMatrix operand, which previously panicked because both asserted their
+// arguments were *mat.Dense.
+func TestInnerOuterProductSparse(t *testing.T) {
+	csr, err := parseSparseTriplets("1,1,3")
+	if err != nil {
+		t.Fatalf("parseSparseTriplets returned error: %v", err)
+	}
+	b := mat.NewDense(1, 1, []float64{4})
+
+	inner, err := innerProduct(csr, b)
+	if err != nil {
+		t.Fatalf("innerProduct returned error: %v", err)
+	}
+	if inner != 12 {
+		t.Errorf("inner = %v, want 12", inner)
+	}
+
+	outer, err := outerProduct(csr, b)
+	if err != nil {
+		t.Fatalf("outerProduct returned error: %v", err)
+	}
+	if outer.At(0, 0) != 12 {
+		t.Errorf("outer[0][0] = %v, want 12", outer.At(0, 0))
+	}
+}